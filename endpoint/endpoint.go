@@ -0,0 +1,110 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TTL is a structure defining the TTL of a DNS record
+type TTL int64
+
+// IsConfigured returns true if TTL is configured, false otherwise
+func (ttl TTL) IsConfigured() bool {
+	return ttl != 0
+}
+
+const (
+	// RecordTypeA is a RecordType enum value
+	RecordTypeA = "A"
+	// RecordTypeCNAME is a RecordType enum value
+	RecordTypeCNAME = "CNAME"
+	// RecordTypeTXT is a RecordType enum value
+	RecordTypeTXT = "TXT"
+)
+
+// ProviderSpecificProperty holds a provider-specific key/value pair that
+// doesn't fit the common Endpoint fields, e.g. an AWS Route53 routing-policy
+// annotation surfaced by a source implementation.
+type ProviderSpecificProperty struct {
+	Key   string
+	Value string
+}
+
+// Endpoint is a high-level representation of a set of DNS records that all
+// have the same name and type.
+type Endpoint struct {
+	// DNSName is the hostname of the DNS record
+	DNSName string
+	// Target is the record value
+	Target string
+	// RecordType is the type of DNS record, e.g. A, CNAME, TXT
+	RecordType string
+	// SetIdentifier distinguishes multiple records with the same DNSName and
+	// RecordType, e.g. the members of a weighted, latency, failover or
+	// geolocation routing policy.
+	SetIdentifier string
+	// RecordTTL is the record's TTL
+	RecordTTL TTL
+	// ProviderSpecific holds provider-specific annotations attached to this
+	// endpoint by a source implementation.
+	ProviderSpecific []ProviderSpecificProperty
+}
+
+// NewEndpoint initialization method to be used to create an endpoint
+func NewEndpoint(dnsName, target, recordType string) *Endpoint {
+	return NewEndpointWithTTL(dnsName, target, recordType, TTL(0))
+}
+
+// NewEndpointWithTTL initialization method to be used to create an endpoint with a TTL struct
+func NewEndpointWithTTL(dnsName, target, recordType string, ttl TTL) *Endpoint {
+	return &Endpoint{
+		DNSName:    strings.TrimSuffix(dnsName, "."),
+		Target:     target,
+		RecordType: recordType,
+		RecordTTL:  ttl,
+	}
+}
+
+// GetProviderSpecificProperty returns the value of the named provider-specific
+// property and whether it was set.
+func (e *Endpoint) GetProviderSpecificProperty(key string) (string, bool) {
+	for _, p := range e.ProviderSpecific {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SetProviderSpecificProperty sets, or replaces, the named provider-specific property.
+func (e *Endpoint) SetProviderSpecificProperty(key, value string) {
+	for i, p := range e.ProviderSpecific {
+		if p.Key == key {
+			e.ProviderSpecific[i].Value = value
+			return
+		}
+	}
+
+	e.ProviderSpecific = append(e.ProviderSpecific, ProviderSpecificProperty{Key: key, Value: value})
+}
+
+func (e *Endpoint) String() string {
+	return fmt.Sprintf("%s %d IN %s %s SetIdentifier=%q", e.DNSName, e.RecordTTL, e.RecordType, e.Target, e.SetIdentifier)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/kubernetes-incubator/external-dns/pkg/apis/externaldns"
+	"github.com/kubernetes-incubator/external-dns/provider"
+)
+
+func main() {
+	cfg, err := externaldns.ParseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	awsConfig, err := newAWSProviderConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := provider.NewAWSProvider(awsConfig); err != nil {
+		log.Fatalf("failed to initialize AWS provider: %v", err)
+	}
+}
+
+// newAWSProviderConfig translates the parsed CLI flags into the AWSConfig
+// NewAWSProvider expects.
+func newAWSProviderConfig(cfg *externaldns.Config) (provider.AWSConfig, error) {
+	vpcs, err := provider.ParseAWSPrivateZoneVPCs(cfg.AWSPrivateZoneVPC)
+	if err != nil {
+		return provider.AWSConfig{}, err
+	}
+
+	return provider.AWSConfig{
+		ZoneTagFilter:        provider.NewZoneTagFilter(cfg.AWSZoneTagFilter),
+		Profile:              cfg.AWSProfile,
+		AssumeRoleARN:        cfg.AWSAssumeRole,
+		AssumeRoleExternalID: cfg.AWSAssumeRoleExternalID,
+		Region:               cfg.AWSRegion,
+		APIEndpoint:          cfg.AWSAPIEndpoint,
+		AccessKeyID:          cfg.AWSAccessKeyID,
+		SecretAccessKey:      cfg.AWSSecretAccessKey,
+		CreateZones:          cfg.AWSCreateZones,
+		DelegationSetID:      cfg.AWSDelegationSetID,
+		PrivateZoneVPCs:      vpcs,
+		BatchChangeInterval:  cfg.AWSBatchChangeInterval,
+		MaxRetries:           cfg.AWSMaxRetries,
+		DryRun:               cfg.DryRun,
+	}, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externaldns holds the command-line configuration for external-dns.
+// Only the flags relevant to the AWS provider are represented in this tree.
+package externaldns
+
+import (
+	"flag"
+	"strings"
+	"time"
+)
+
+// Config holds the configuration for external-dns sourced from CLI flags.
+type Config struct {
+	DryRun bool
+
+	AWSZoneTagFilter        []string
+	AWSProfile              string
+	AWSAssumeRole           string
+	AWSAssumeRoleExternalID string
+	AWSRegion               string
+	AWSAPIEndpoint          string
+	AWSAccessKeyID          string
+	AWSSecretAccessKey      string
+	AWSCreateZones          bool
+	AWSDelegationSetID      string
+	AWSPrivateZoneVPC       []string
+	AWSBatchChangeInterval  time.Duration
+	AWSMaxRetries           int
+}
+
+// stringSliceValue implements flag.Value for a repeatable string flag.
+type stringSliceValue struct {
+	values *[]string
+}
+
+func newStringSliceValue(values *[]string) *stringSliceValue {
+	return &stringSliceValue{values: values}
+}
+
+func (s *stringSliceValue) String() string {
+	if s.values == nil {
+		return ""
+	}
+	return strings.Join(*s.values, ",")
+}
+
+func (s *stringSliceValue) Set(v string) error {
+	*s.values = append(*s.values, v)
+	return nil
+}
+
+// ParseFlags parses the given command-line arguments into a Config.
+func ParseFlags(args []string) (*Config, error) {
+	cfg := &Config{}
+
+	fs := flag.NewFlagSet("external-dns", flag.ContinueOnError)
+
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "run without making any changes")
+
+	fs.Var(newStringSliceValue(&cfg.AWSZoneTagFilter), "aws-zone-tags-filter", "only consider hosted zones carrying this key=value tag; repeatable")
+	fs.StringVar(&cfg.AWSProfile, "aws-profile", "", "the AWS profile to use")
+	fs.StringVar(&cfg.AWSAssumeRole, "aws-assume-role", "", "the ARN of an IAM role to assume before talking to Route53")
+	fs.StringVar(&cfg.AWSAssumeRoleExternalID, "aws-assume-role-external-id", "", "the external ID to use when assuming -aws-assume-role")
+	fs.StringVar(&cfg.AWSRegion, "aws-region", "", "the AWS region to target, overriding the environment/shared config")
+	fs.StringVar(&cfg.AWSAPIEndpoint, "aws-api-endpoint", "", "a custom Route53 API endpoint, e.g. for LocalStack")
+	fs.StringVar(&cfg.AWSAccessKeyID, "aws-access-key-id", "", "a static AWS access key ID")
+	fs.StringVar(&cfg.AWSSecretAccessKey, "aws-secret-access-key", "", "a static AWS secret access key")
+	fs.BoolVar(&cfg.AWSCreateZones, "aws-create-zones", false, "automatically create the parent hosted zone for records that don't match an existing one")
+	fs.StringVar(&cfg.AWSDelegationSetID, "aws-delegation-set-id", "", "the reusable delegation set attached to zones created by -aws-create-zones")
+	fs.Var(newStringSliceValue(&cfg.AWSPrivateZoneVPC), "aws-private-zone-vpc", "vpcid:region attached to private zones created by -aws-create-zones; repeatable")
+	fs.DurationVar(&cfg.AWSBatchChangeInterval, "aws-batch-change-interval", 0, "base retry delay for a throttled Route53 batch submission")
+	fs.IntVar(&cfg.AWSMaxRetries, "aws-max-retries", 0, "maximum retry attempts for a throttled Route53 batch submission")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
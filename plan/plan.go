@@ -0,0 +1,123 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+// Plan can convert a list of desired and current records to a series of create,
+// update and delete actions.
+type Plan struct {
+	// List of current records
+	Current []*endpoint.Endpoint
+	// List of desired records
+	Desired []*endpoint.Endpoint
+	// Calculated Changes to be applied
+	Changes *Changes
+}
+
+// Changes holds lists of actions to be executed by providers
+type Changes struct {
+	// Records that need to be created
+	Create []*endpoint.Endpoint
+	// Records that need to be updated (current data)
+	UpdateOld []*endpoint.Endpoint
+	// Records that need to be updated (desired data)
+	UpdateNew []*endpoint.Endpoint
+	// Records that need to be deleted
+	Delete []*endpoint.Endpoint
+}
+
+// planKey identifies a distinct DNS record group. Two endpoints sharing a
+// DNSName and RecordType are still distinct records if their SetIdentifier
+// differs, e.g. the members of a weighted or failover routing policy.
+type planKey struct {
+	dnsName       string
+	recordType    string
+	setIdentifier string
+}
+
+func keyFor(ep *endpoint.Endpoint) planKey {
+	return planKey{
+		dnsName:       ep.DNSName,
+		recordType:    ep.RecordType,
+		setIdentifier: ep.SetIdentifier,
+	}
+}
+
+// Calculate computes the create/update/delete actions needed to take Current
+// to Desired and stores them in p.Changes, returning p for convenience.
+func (p *Plan) Calculate() *Plan {
+	changes := &Changes{}
+
+	current := make(map[planKey]*endpoint.Endpoint, len(p.Current))
+	for _, ep := range p.Current {
+		current[keyFor(ep)] = ep
+	}
+
+	desired := make(map[planKey]*endpoint.Endpoint, len(p.Desired))
+	for _, ep := range p.Desired {
+		desired[keyFor(ep)] = ep
+	}
+
+	for key, ep := range desired {
+		if existing, found := current[key]; found {
+			if existing.Target != ep.Target || existing.RecordTTL != ep.RecordTTL || !providerSpecificEqual(existing.ProviderSpecific, ep.ProviderSpecific) {
+				changes.UpdateOld = append(changes.UpdateOld, existing)
+				changes.UpdateNew = append(changes.UpdateNew, ep)
+			}
+			continue
+		}
+
+		changes.Create = append(changes.Create, ep)
+	}
+
+	for key, ep := range current {
+		if _, found := desired[key]; !found {
+			changes.Delete = append(changes.Delete, ep)
+		}
+	}
+
+	p.Changes = changes
+
+	return p
+}
+
+// providerSpecificEqual reports whether a and b carry the same key/value
+// pairs, regardless of order. A provider-specific routing-policy annotation
+// (weight, region, failover, geolocation, health check, ...) is part of a
+// record's identity for update purposes, so changing only it must still
+// produce an UpdateOld/UpdateNew pair.
+func providerSpecificEqual(a, b []endpoint.ProviderSpecificProperty) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	am := make(map[string]string, len(a))
+	for _, p := range a {
+		am[p.Key] = p.Value
+	}
+
+	for _, p := range b {
+		if v, ok := am[p.Key]; !ok || v != p.Value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+func TestCalculateTreatsDifferingSetIdentifiersAsDistinctRecords(t *testing.T) {
+	primary := endpoint.NewEndpoint("weighted.example.com", "1.2.3.4", endpoint.RecordTypeA)
+	primary.SetIdentifier = "primary"
+	primary.SetProviderSpecificProperty("aws/weight", "10")
+
+	secondary := endpoint.NewEndpoint("weighted.example.com", "5.6.7.8", endpoint.RecordTypeA)
+	secondary.SetIdentifier = "secondary"
+	secondary.SetProviderSpecificProperty("aws/weight", "90")
+
+	p := (&Plan{
+		Current: []*endpoint.Endpoint{primary, secondary},
+		Desired: []*endpoint.Endpoint{primary, secondary},
+	}).Calculate()
+
+	if len(p.Changes.Create) != 0 || len(p.Changes.UpdateOld) != 0 || len(p.Changes.Delete) != 0 {
+		t.Fatalf("expected no changes for two unchanged, distinct SetIdentifiers, got %+v", p.Changes)
+	}
+}
+
+func TestCalculateDetectsProviderSpecificOnlyChange(t *testing.T) {
+	current := endpoint.NewEndpoint("weighted.example.com", "1.2.3.4", endpoint.RecordTypeA)
+	current.SetIdentifier = "primary"
+	current.SetProviderSpecificProperty("aws/weight", "10")
+
+	desired := endpoint.NewEndpoint("weighted.example.com", "1.2.3.4", endpoint.RecordTypeA)
+	desired.SetIdentifier = "primary"
+	desired.SetProviderSpecificProperty("aws/weight", "50")
+
+	p := (&Plan{
+		Current: []*endpoint.Endpoint{current},
+		Desired: []*endpoint.Endpoint{desired},
+	}).Calculate()
+
+	if len(p.Changes.UpdateOld) != 1 || len(p.Changes.UpdateNew) != 1 {
+		t.Fatalf("expected a weight-only change to produce an update, got %+v", p.Changes)
+	}
+	if p.Changes.UpdateOld[0] != current || p.Changes.UpdateNew[0] != desired {
+		t.Errorf("UpdateOld/UpdateNew don't reference the expected endpoints")
+	}
+}
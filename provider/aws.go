@@ -17,10 +17,17 @@ limitations under the License.
 package provider
 
 import (
+	"fmt"
+	"math/rand"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -33,7 +40,30 @@ const (
 	elbHostnameSuffix    = ".elb.amazonaws.com"
 	evaluateTargetHealth = true
 	recordTTL            = 300
-	maxChangeCount       = 4000
+
+	// From the Route53 docs: https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+	// and https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DNSLimitations.html#limits-api-requests-changeresourcerecordsets
+	// a ChangeResourceRecordSets request cannot contain more than 1000 ResourceRecord
+	// elements, nor more than 32000 characters of ResourceRecord.Value summed across
+	// all of the changes in the request. An UPSERT counts twice against both limits
+	// because Route53 implements it as a DELETE followed by a CREATE.
+	route53MaxChangeCount    = 1000
+	route53MaxValueCharCount = 32000
+
+	// ProviderSpecific keys populated by source implementations (via the
+	// external-dns.alpha.kubernetes.io/aws-* annotations) to request a Route53
+	// routing policy other than the default simple routing.
+	providerSpecificWeight                 = "aws/weight"
+	providerSpecificRegion                 = "aws/region"
+	providerSpecificFailover               = "aws/failover"
+	providerSpecificGeolocationCountryCode = "aws/geolocation-country-code"
+	providerSpecificHealthCheckID          = "aws/health-check-id"
+
+	// defaults for the retry behavior of a throttled ChangeResourceRecordSets
+	// call, used when AWSConfig doesn't override them.
+	defaultBatchChangeInterval = time.Second
+	defaultMaxRetries          = 5
+	maxBatchChangeInterval     = 60 * time.Second
 )
 
 var (
@@ -63,6 +93,8 @@ type Route53API interface {
 	ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error)
 	CreateHostedZone(*route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error)
 	ListHostedZonesPages(input *route53.ListHostedZonesInput, fn func(resp *route53.ListHostedZonesOutput, lastPage bool) (shouldContinue bool)) error
+	ListTagsForResources(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error)
+	AssociateVPCWithHostedZone(input *route53.AssociateVPCWithHostedZoneInput) (*route53.AssociateVPCWithHostedZoneOutput, error)
 }
 
 // AWSProvider is an implementation of Provider for AWS Route53.
@@ -73,10 +105,81 @@ type AWSProvider struct {
 	domainFilter DomainFilter
 	// filter hosted zones by type (e.g. private or public)
 	zoneTypeFilter ZoneTypeFilter
+	// only consider hosted zones that carry all of these tags
+	zoneTagFilter ZoneTagFilter
+	// create the parent hosted zone for records that don't match an existing one
+	createZones bool
+	// delegation set attached to zones created by createZones
+	delegationSetID string
+	// VPCs attached to private zones created by createZones
+	privateZoneVPCs []*route53.VPC
+	// base delay and retry cap for a throttled ChangeResourceRecordSets call
+	batchChangeInterval time.Duration
+	maxRetries          int
+}
+
+// AWSConfig contains configuration to create a new AWS provider.
+type AWSConfig struct {
+	DomainFilter   DomainFilter
+	ZoneTypeFilter ZoneTypeFilter
+	ZoneTagFilter  ZoneTagFilter
+	// Profile is the name of the shared config/credentials profile to use, as
+	// in `~/.aws/credentials`. Leave empty to use the default credential chain.
+	Profile string
+	// AssumeRoleARN, if set, is assumed via STS on top of whatever credentials
+	// Profile (or the default chain) resolves to, e.g. to reach a hosted
+	// zone in a different AWS account.
+	AssumeRoleARN        string
+	AssumeRoleExternalID string
+	// AccessKeyID and SecretAccessKey provide static credentials; leave both
+	// empty to fall back to the default credential chain.
+	AccessKeyID     string
+	SecretAccessKey string
+	// Region overrides the region resolved from the environment/shared
+	// config, e.g. for GovCloud or a region with no local config.
+	Region string
+	// APIEndpoint overrides the Route53 endpoint, e.g. to target LocalStack.
+	APIEndpoint string
+	// CreateZones opts into automatically creating the parent hosted zone for
+	// any desired record whose DNS name doesn't match an existing zone.
+	CreateZones bool
+	// DelegationSetID, if set, is attached to zones CreateZones creates so
+	// that their NS records stay stable across recreations.
+	DelegationSetID string
+	// PrivateZoneVPCs, if set, is attached to private zones CreateZones creates.
+	PrivateZoneVPCs []*route53.VPC
+	// BatchChangeInterval is the base delay before retrying a Route53 batch
+	// submission that was throttled; it doubles on each subsequent attempt up
+	// to a 60s cap. Defaults to 1s if zero.
+	BatchChangeInterval time.Duration
+	// MaxRetries caps how many times a throttled batch submission is retried.
+	// Defaults to 5 if zero.
+	MaxRetries int
+	DryRun     bool
+}
+
+// ParseAWSPrivateZoneVPCs parses the "vpcid:region" pairs accepted by
+// --aws-private-zone-vpc into the VPC structs CreateHostedZone expects.
+func ParseAWSPrivateZoneVPCs(vpcs []string) ([]*route53.VPC, error) {
+	parsed := make([]*route53.VPC, 0, len(vpcs))
+
+	for _, vpc := range vpcs {
+		parts := strings.SplitN(vpc, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid --aws-private-zone-vpc value %q, expected vpcid:region", vpc)
+		}
+
+		parsed = append(parsed, &route53.VPC{
+			VPCId:     aws.String(parts[0]),
+			VPCRegion: aws.String(parts[1]),
+		})
+	}
+
+	return parsed, nil
 }
 
 // NewAWSProvider initializes a new AWS Route53 based Provider.
-func NewAWSProvider(domainFilter DomainFilter, zoneTypeFilter ZoneTypeFilter, dryRun bool) (*AWSProvider, error) {
+func NewAWSProvider(awsConfig AWSConfig) (*AWSProvider, error) {
 	config := aws.NewConfig()
 
 	config = config.WithHTTPClient(
@@ -88,19 +191,58 @@ func NewAWSProvider(domainFilter DomainFilter, zoneTypeFilter ZoneTypeFilter, dr
 		}),
 	)
 
+	if awsConfig.Region != "" {
+		config = config.WithRegion(awsConfig.Region)
+	}
+
+	if awsConfig.APIEndpoint != "" {
+		config = config.WithEndpoint(awsConfig.APIEndpoint)
+	}
+
+	if awsConfig.AccessKeyID != "" || awsConfig.SecretAccessKey != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(awsConfig.AccessKeyID, awsConfig.SecretAccessKey, ""))
+	}
+
 	session, err := session.NewSessionWithOptions(session.Options{
 		Config:            *config,
+		Profile:           awsConfig.Profile,
 		SharedConfigState: session.SharedConfigEnable,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if awsConfig.AssumeRoleARN != "" {
+		log.Infof("Assuming role: %s", awsConfig.AssumeRoleARN)
+
+		session.Config.WithCredentials(stscreds.NewCredentials(session, awsConfig.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if awsConfig.AssumeRoleExternalID != "" {
+				p.ExternalID = aws.String(awsConfig.AssumeRoleExternalID)
+			}
+		}))
+	}
+
+	batchChangeInterval := awsConfig.BatchChangeInterval
+	if batchChangeInterval <= 0 {
+		batchChangeInterval = defaultBatchChangeInterval
+	}
+
+	maxRetries := awsConfig.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
 	provider := &AWSProvider{
-		client:         route53.New(session),
-		domainFilter:   domainFilter,
-		zoneTypeFilter: zoneTypeFilter,
-		dryRun:         dryRun,
+		client:              route53.New(session),
+		domainFilter:        awsConfig.DomainFilter,
+		zoneTypeFilter:      awsConfig.ZoneTypeFilter,
+		zoneTagFilter:       awsConfig.ZoneTagFilter,
+		createZones:         awsConfig.CreateZones,
+		delegationSetID:     awsConfig.DelegationSetID,
+		privateZoneVPCs:     awsConfig.PrivateZoneVPCs,
+		batchChangeInterval: batchChangeInterval,
+		maxRetries:          maxRetries,
+		dryRun:              awsConfig.DryRun,
 	}
 
 	return provider, nil
@@ -131,9 +273,79 @@ func (p *AWSProvider) Zones() (map[string]*route53.HostedZone, error) {
 		return nil, err
 	}
 
+	if !p.zoneTagFilter.IsEmpty() {
+		err = p.tagFilterZones(zones)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return zones, nil
 }
 
+// route53TagsForResourcesLimit is the maximum number of resource IDs Route53
+// accepts in a single ListTagsForResources call.
+const route53TagsForResourcesLimit = 10
+
+// tagFilterZones removes, in place, every zone from zones whose tags don't
+// satisfy p.zoneTagFilter. Tags are fetched in batches of up to
+// route53TagsForResourcesLimit zones per call to keep API usage bounded.
+func (p *AWSProvider) tagFilterZones(zones map[string]*route53.HostedZone) error {
+	zoneIDs := make([]string, 0, len(zones))
+	for id := range zones {
+		zoneIDs = append(zoneIDs, id)
+	}
+
+	for len(zoneIDs) > 0 {
+		batchSize := route53TagsForResourcesLimit
+		if batchSize > len(zoneIDs) {
+			batchSize = len(zoneIDs)
+		}
+		batch := zoneIDs[:batchSize]
+		zoneIDs = zoneIDs[batchSize:]
+
+		resourceIDs := make([]*string, 0, len(batch))
+		// Route53 echoes ResourceId back bare (no "/hostedzone/" prefix), but
+		// zones is keyed by the prefixed ID ListHostedZones returns, so keep a
+		// reverse lookup to delete the right entry below.
+		originalID := make(map[string]string, len(batch))
+		for _, id := range batch {
+			clean := cleanZoneID(id)
+			resourceIDs = append(resourceIDs, aws.String(clean))
+			originalID[clean] = id
+		}
+
+		out, err := p.client.ListTagsForResources(&route53.ListTagsForResourcesInput{
+			ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+			ResourceIds:  resourceIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, rts := range out.ResourceTagSets {
+			id := cleanZoneID(aws.StringValue(rts.ResourceId))
+
+			tags := make(map[string]string, len(rts.Tags))
+			for _, tag := range rts.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+
+			if !p.zoneTagFilter.Match(tags) {
+				delete(zones, originalID[id])
+			}
+		}
+	}
+
+	return nil
+}
+
+// cleanZoneID strips the "/hostedzone/" prefix ListHostedZones returns, since
+// APIs like ListTagsForResources require the bare zone ID.
+func cleanZoneID(id string) string {
+	return strings.TrimPrefix(id, "/hostedzone/")
+}
+
 // wildcardUnescape converts \\052.abc back to *.abc
 // Route53 stores wildcards escaped: http://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DomainNameFormat.html?shortFooter=true#domain-name-format-asterisk
 func wildcardUnescape(s string) string {
@@ -165,11 +377,15 @@ func (p *AWSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 			}
 
 			for _, rr := range r.ResourceRecords {
-				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(wildcardUnescape(aws.StringValue(r.Name)), aws.StringValue(rr.Value), aws.StringValue(r.Type), ttl))
+				ep := endpoint.NewEndpointWithTTL(wildcardUnescape(aws.StringValue(r.Name)), aws.StringValue(rr.Value), aws.StringValue(r.Type), ttl)
+				setEndpointRoutingPolicy(ep, r)
+				endpoints = append(endpoints, ep)
 			}
 
 			if r.AliasTarget != nil {
-				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(wildcardUnescape(aws.StringValue(r.Name)), aws.StringValue(r.AliasTarget.DNSName), endpoint.RecordTypeCNAME, ttl))
+				ep := endpoint.NewEndpointWithTTL(wildcardUnescape(aws.StringValue(r.Name)), aws.StringValue(r.AliasTarget.DNSName), endpoint.RecordTypeCNAME, ttl)
+				setEndpointRoutingPolicy(ep, r)
+				endpoints = append(endpoints, ep)
 			}
 		}
 
@@ -228,65 +444,164 @@ func (p *AWSProvider) submitChanges(changes []*route53.Change) error {
 		return err
 	}
 
+	if p.createZones && !p.dryRun {
+		if err := p.createMissingZones(zones, changes); err != nil {
+			return err
+		}
+	}
+
 	// separate into per-zone change sets to be passed to the API.
 	changesByZone := changesByZone(zones, changes)
 
 	for z, cs := range changesByZone {
-		limCs := limitChangeSet(cs, maxChangeCount)
-
-		for _, c := range limCs {
-			log.Infof("Desired change: %s %s %s", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type)
+		batches, err := batchChangeSet(sortChangesByActionNameType(cs), route53MaxChangeCount, route53MaxValueCharCount)
+		if err != nil {
+			return err
 		}
 
-		if !p.dryRun {
-			params := &route53.ChangeResourceRecordSetsInput{
-				HostedZoneId: aws.String(z),
-				ChangeBatch: &route53.ChangeBatch{
-					Changes: limCs,
-				},
+		for i, batch := range batches {
+			for _, c := range batch {
+				log.Infof("Desired change: %s %s %s", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type)
 			}
 
-			if _, err := p.client.ChangeResourceRecordSets(params); err != nil {
-				log.Error(err) //TODO(ideahitme): consider changing the interface in cases when this error might be a concern for other components
-				continue
+			if !p.dryRun {
+				params := &route53.ChangeResourceRecordSetsInput{
+					HostedZoneId: aws.String(z),
+					ChangeBatch: &route53.ChangeBatch{
+						Changes: batch,
+					},
+				}
+
+				if _, err := p.changeResourceRecordSetsWithRetry(params); err != nil {
+					return fmt.Errorf("failed to apply change batch %d/%d in zone %s: %v", i+1, len(batches), aws.StringValue(zones[z].Name), err)
+				}
+				log.Infof("Change batch %d/%d in zone %s was successfully applied", i+1, len(batches), aws.StringValue(zones[z].Name))
 			}
-			log.Infof("Record in zone %s were successfully updated", aws.StringValue(zones[z].Name))
 		}
 	}
 
 	return nil
 }
 
-func limitChangeSet(cs []*route53.Change, limit int) []*route53.Change {
-	if len(cs) <= limit {
-		return cs
+// changeResourceRecordSetsWithRetry calls ChangeResourceRecordSets, retrying
+// throttling errors with exponential backoff and full jitter (base
+// p.batchChangeInterval, doubling up to maxBatchChangeInterval) for up to
+// p.maxRetries retries (p.maxRetries+1 calls in all). Any other error, or a throttling error that
+// exhausts its retries, is returned immediately.
+func (p *AWSProvider) changeResourceRecordSetsWithRetry(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	interval := p.batchChangeInterval
+
+	var err error
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		var out *route53.ChangeResourceRecordSetsOutput
+		out, err = p.client.ChangeResourceRecordSets(input)
+		if err == nil {
+			return out, nil
+		}
+
+		if !isThrottlingError(err) || attempt == p.maxRetries {
+			return nil, err
+		}
+
+		delay := fullJitter(interval)
+		log.Warnf("Route53 change batch throttled (attempt %d/%d), retrying in %s: %v", attempt+1, p.maxRetries, delay, err)
+		time.Sleep(delay)
+
+		interval *= 2
+		if interval > maxBatchChangeInterval {
+			interval = maxBatchChangeInterval
+		}
+	}
+
+	return nil, err
+}
+
+// isThrottlingError returns true for the awserr.Error codes and HTTP 5xx
+// statuses Route53 returns when a client is being rate limited.
+func isThrottlingError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	switch awsErr.Code() {
+	case "Throttling", "ThrottlingException", "PriorRequestNotComplete", "RequestLimitExceeded":
+		return true
+	}
+
+	if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() >= 500 {
+		return true
+	}
+
+	return false
+}
+
+// fullJitter picks a random delay in [0, max), per the "full jitter" backoff
+// strategy: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func fullJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// changeCost returns how much a single change counts against Route53's per-batch
+// limits: the number of ResourceRecord values it carries (an alias target counts
+// as one) and the summed length of their Value strings. An UPSERT is billed as a DELETE plus a CREATE, so its cost
+// is doubled.
+func changeCost(c *route53.Change) (recordCount, valueChars int) {
+	for _, rr := range c.ResourceRecordSet.ResourceRecords {
+		recordCount++
+		valueChars += len(aws.StringValue(rr.Value))
 	}
 
-	log.Warningf("Initial change batch count is %d", len(cs))
+	if c.ResourceRecordSet.AliasTarget != nil {
+		recordCount++
+	}
 
-	changesByName := make(map[string][]*route53.Change, 0)
-	for _, v := range cs {
-		changesByName[*v.ResourceRecordSet.Name] = append(changesByName[*v.ResourceRecordSet.Name], v)
+	if aws.StringValue(c.Action) == route53.ChangeActionUpsert {
+		recordCount *= 2
+		valueChars *= 2
 	}
 
-	names := make([]string, 0)
-	for v := range changesByName {
-		names = append(names, v)
+	return recordCount, valueChars
+}
+
+// batchChangeSet splits cs into ordered batches that each stay within Route53's
+// limits on the number of ResourceRecord values (maxCount) and the total character
+// size of their Values (maxChars), greedily packing changes in the order given. A
+// single change that alone exceeds either limit can never fit in any batch, so it
+// is reported as an error rather than silently dropped or submitted oversized.
+func batchChangeSet(cs []*route53.Change, maxCount, maxChars int) ([][]*route53.Change, error) {
+	if len(cs) == 0 {
+		return nil, nil
 	}
-	sort.Strings(names)
 
-	limCs := make([]*route53.Change, 0)
-	for i := 0; i < len(names); i++ {
-		changes := changesByName[names[i]]
-		if (limit - len(limCs)) >= len(changes) {
-			limCs = append(limCs, changes...)
+	var batches [][]*route53.Change
+	var batch []*route53.Change
+	var batchCount, batchChars int
+
+	for _, c := range cs {
+		count, chars := changeCost(c)
+		if count > maxCount || chars > maxChars {
+			return nil, fmt.Errorf("change for %s %s exceeds Route53 batch limits on its own (%d records, %d value chars)", aws.StringValue(c.ResourceRecordSet.Name), aws.StringValue(c.ResourceRecordSet.Type), count, chars)
+		}
+
+		if len(batch) > 0 && (batchCount+count > maxCount || batchChars+chars > maxChars) {
+			batches = append(batches, batch)
+			batch = nil
+			batchCount, batchChars = 0, 0
 		}
+
+		batch = append(batch, c)
+		batchCount += count
+		batchChars += chars
 	}
-	limCs = sortChangesByActionNameType(limCs)
 
-	log.Warningf("Limited change batch count to %d", len(limCs))
+	batches = append(batches, batch)
 
-	return limCs
+	return batches, nil
 }
 
 func sortChangesByActionNameType(cs []*route53.Change) []*route53.Change {
@@ -340,6 +655,111 @@ func changesByZone(zones map[string]*route53.HostedZone, changeSet []*route53.Ch
 	return changes
 }
 
+// createMissingZones creates, via CreateHostedZone, the parent zone for every
+// change whose DNS name doesn't match a zone already in zones, adding each
+// newly created zone to zones so a subsequent changesByZone picks it up. At
+// most one zone is created per distinct parent domain name.
+func (p *AWSProvider) createMissingZones(zones map[string]*route53.HostedZone, changeSet []*route53.Change) error {
+	zoneNameIDMapper := zoneIDName{}
+	for _, z := range zones {
+		zoneNameIDMapper.Add(aws.StringValue(z.Id), aws.StringValue(z.Name))
+	}
+
+	requested := make(map[string]bool)
+
+	for _, c := range changeSet {
+		if aws.StringValue(c.Action) == route53.ChangeActionDelete {
+			// Nothing to reconcile if the parent zone doesn't exist: don't
+			// create (and start billing for) a zone just to process a no-op delete.
+			continue
+		}
+
+		hostname := ensureTrailingDot(aws.StringValue(c.ResourceRecordSet.Name))
+
+		if zoneID, _ := zoneNameIDMapper.FindZone(hostname); zoneID != "" {
+			continue
+		}
+
+		zoneName := parentZoneName(hostname)
+		if requested[zoneName] {
+			continue
+		}
+		requested[zoneName] = true
+
+		zone, err := p.createHostedZone(zoneName)
+		if err != nil {
+			return err
+		}
+
+		zones[aws.StringValue(zone.Id)] = zone
+		zoneNameIDMapper.Add(aws.StringValue(zone.Id), aws.StringValue(zone.Name))
+	}
+
+	return nil
+}
+
+// parentZoneName returns the domain a new hosted zone should be created for
+// to hold hostname: hostname with its leftmost label stripped off.
+func parentZoneName(hostname string) string {
+	labels := strings.Split(strings.TrimSuffix(hostname, "."), ".")
+	if len(labels) <= 1 {
+		return ensureTrailingDot(hostname)
+	}
+
+	return ensureTrailingDot(strings.Join(labels[1:], "."))
+}
+
+// createHostedZone creates a new Route53 hosted zone for zoneName, attaching
+// the configured delegation set and/or VPC, and logs its name servers so an
+// operator can update the parent domain's delegation.
+func (p *AWSProvider) createHostedZone(zoneName string) (*route53.HostedZone, error) {
+	input := &route53.CreateHostedZoneInput{
+		Name:            aws.String(zoneName),
+		CallerReference: aws.String(fmt.Sprintf("external-dns-%s-%d", zoneName, time.Now().UnixNano())),
+	}
+
+	if p.delegationSetID != "" {
+		input.DelegationSetId = aws.String(p.delegationSetID)
+	}
+
+	if len(p.privateZoneVPCs) > 0 {
+		input.VPC = p.privateZoneVPCs[0]
+	}
+
+	out, err := p.client.CreateHostedZone(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating hosted zone for %s: %v", zoneName, err)
+	}
+
+	// Route53 doesn't return a DelegationSet for private zones, so guard
+	// against it being nil rather than assuming every response has one.
+	var nameServers []string
+	if out.DelegationSet != nil {
+		nameServers = make([]string, 0, len(out.DelegationSet.NameServers))
+		for _, ns := range out.DelegationSet.NameServers {
+			nameServers = append(nameServers, aws.StringValue(ns))
+		}
+	}
+
+	// CreateHostedZone only accepts a single VPC; associate any remaining
+	// configured VPCs individually so --aws-private-zone-vpc can list more than one.
+	if len(p.privateZoneVPCs) > 1 {
+		for _, vpc := range p.privateZoneVPCs[1:] {
+			if _, err := p.client.AssociateVPCWithHostedZone(&route53.AssociateVPCWithHostedZoneInput{
+				HostedZoneId: out.HostedZone.Id,
+				VPC:          vpc,
+			}); err != nil {
+				return nil, fmt.Errorf("failed associating VPC %s with hosted zone %s: %v", aws.StringValue(vpc.VPCId), zoneName, err)
+			}
+		}
+	}
+
+	log.Infof("Created hosted zone %s for %s; update the parent domain's delegation to these name servers: %s",
+		aws.StringValue(out.HostedZone.Id), zoneName, strings.Join(nameServers, ", "))
+
+	return out.HostedZone, nil
+}
+
 // newChanges returns a collection of Changes based on the given records and action.
 func newChanges(action string, endpoints []*endpoint.Endpoint) []*route53.Change {
 	changes := make([]*route53.Change, 0, len(endpoints))
@@ -362,6 +782,11 @@ func newChange(action string, endpoint *endpoint.Endpoint) *route53.Change {
 		},
 	}
 
+	if endpoint.SetIdentifier != "" {
+		change.ResourceRecordSet.SetIdentifier = aws.String(endpoint.SetIdentifier)
+		setRoutingPolicy(change.ResourceRecordSet, endpoint)
+	}
+
 	if isAWSLoadBalancer(endpoint) {
 		change.ResourceRecordSet.Type = aws.String(route53.RRTypeA)
 		change.ResourceRecordSet.AliasTarget = &route53.AliasTarget{
@@ -386,7 +811,63 @@ func newChange(action string, endpoint *endpoint.Endpoint) *route53.Change {
 	return change
 }
 
-// isAWSLoadBalancer determines if a given hostname belongs to an AWS load balancer.
+// setEndpointRoutingPolicy copies a resource record set's SetIdentifier and
+// routing-policy fields onto ep, the inverse of setRoutingPolicy, so that
+// weighted, latency, failover, and geolocation records round-trip correctly.
+func setEndpointRoutingPolicy(ep *endpoint.Endpoint, rrset *route53.ResourceRecordSet) {
+	ep.SetIdentifier = aws.StringValue(rrset.SetIdentifier)
+
+	if rrset.Weight != nil {
+		ep.SetProviderSpecificProperty(providerSpecificWeight, strconv.FormatInt(*rrset.Weight, 10))
+	}
+	if rrset.Region != nil {
+		ep.SetProviderSpecificProperty(providerSpecificRegion, aws.StringValue(rrset.Region))
+	}
+	if rrset.Failover != nil {
+		ep.SetProviderSpecificProperty(providerSpecificFailover, aws.StringValue(rrset.Failover))
+	}
+	if rrset.GeoLocation != nil && rrset.GeoLocation.CountryCode != nil {
+		ep.SetProviderSpecificProperty(providerSpecificGeolocationCountryCode, aws.StringValue(rrset.GeoLocation.CountryCode))
+	}
+	if rrset.HealthCheckId != nil {
+		ep.SetProviderSpecificProperty(providerSpecificHealthCheckID, aws.StringValue(rrset.HealthCheckId))
+	}
+}
+
+// setRoutingPolicy populates rrset with whichever Route53 routing policy the
+// endpoint's ProviderSpecific properties request. Every property that is
+// present sets its corresponding field; it is not checked that only one of
+// weighted, latency, failover, and geolocation routing is requested. A
+// missing property simply leaves the corresponding field unset.
+func setRoutingPolicy(rrset *route53.ResourceRecordSet, ep *endpoint.Endpoint) {
+	if weight, ok := ep.GetProviderSpecificProperty(providerSpecificWeight); ok {
+		if w, err := strconv.ParseInt(weight, 10, 64); err == nil {
+			rrset.Weight = aws.Int64(w)
+		} else {
+			log.Warnf("Failed parsing value of %s annotation on %s: %v", providerSpecificWeight, ep.DNSName, err)
+		}
+	}
+
+	if region, ok := ep.GetProviderSpecificProperty(providerSpecificRegion); ok {
+		rrset.Region = aws.String(region)
+	}
+
+	if failover, ok := ep.GetProviderSpecificProperty(providerSpecificFailover); ok {
+		rrset.Failover = aws.String(failover)
+	}
+
+	if country, ok := ep.GetProviderSpecificProperty(providerSpecificGeolocationCountryCode); ok {
+		rrset.GeoLocation = &route53.GeoLocation{CountryCode: aws.String(country)}
+	}
+
+	if healthCheckID, ok := ep.GetProviderSpecificProperty(providerSpecificHealthCheckID); ok {
+		rrset.HealthCheckId = aws.String(healthCheckID)
+	}
+}
+
+// isAWSLoadBalancer determines if a given hostname belongs to an AWS load
+// balancer. GovCloud ELBs have no entry in canonicalHostedZones, so they
+// already fall back to a plain CNAME here without a separate GovCloud guard.
 func isAWSLoadBalancer(ep *endpoint.Endpoint) bool {
 	if ep.RecordType == endpoint.RecordTypeCNAME {
 		return canonicalHostedZone(ep.Target) != ""
@@ -0,0 +1,338 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+func changeWithValues(action, name string, values ...string) *route53.Change {
+	records := make([]*route53.ResourceRecord, 0, len(values))
+	for _, v := range values {
+		records = append(records, &route53.ResourceRecord{Value: aws.String(v)})
+	}
+
+	return &route53.Change{
+		Action: aws.String(action),
+		ResourceRecordSet: &route53.ResourceRecordSet{
+			Name:            aws.String(name),
+			Type:            aws.String(route53.RRTypeA),
+			ResourceRecords: records,
+		},
+	}
+}
+
+func TestBatchChangeSetRespectsLimits(t *testing.T) {
+	changes := []*route53.Change{
+		changeWithValues(route53.ChangeActionCreate, "a.example.com.", "1.2.3.4"),
+		changeWithValues(route53.ChangeActionUpsert, "b.example.com.", "5.6.7.8"),
+		changeWithValues(route53.ChangeActionCreate, "c.example.com.", strings.Repeat("9", 100)),
+		changeWithValues(route53.ChangeActionDelete, "d.example.com.", "1.1.1.1", "2.2.2.2", "3.3.3.3"),
+	}
+
+	const maxCount = 4
+	const maxChars = 150
+
+	batches, err := batchChangeSet(changes, maxCount, maxChars)
+	if err != nil {
+		t.Fatalf("batchChangeSet returned an error: %v", err)
+	}
+
+	var total int
+	for _, batch := range batches {
+		var count, chars int
+		for _, c := range batch {
+			n, v := changeCost(c)
+			count += n
+			chars += v
+		}
+
+		if count > maxCount {
+			t.Errorf("batch exceeds record count limit: %d > %d", count, maxCount)
+		}
+		if chars > maxChars {
+			t.Errorf("batch exceeds value char limit: %d > %d", chars, maxChars)
+		}
+
+		total += len(batch)
+	}
+
+	if total != len(changes) {
+		t.Errorf("expected all %d changes to be present across batches, got %d", len(changes), total)
+	}
+}
+
+func TestBatchChangeSetRejectsOversizedChange(t *testing.T) {
+	oversized := changeWithValues(route53.ChangeActionCreate, "big.example.com.", strings.Repeat("x", route53MaxValueCharCount+1))
+
+	_, err := batchChangeSet([]*route53.Change{oversized}, route53MaxChangeCount, route53MaxValueCharCount)
+	if err == nil {
+		t.Fatal("expected an error for a change that can never fit in a batch, got nil")
+	}
+}
+
+func TestBatchChangeSetDoublesUpsertCost(t *testing.T) {
+	upsert := changeWithValues(route53.ChangeActionUpsert, "a.example.com.", strings.Repeat("x", 100))
+
+	count, chars := changeCost(upsert)
+	if count != 2 {
+		t.Errorf("expected an UPSERT to cost 2 record ops, got %d", count)
+	}
+	if chars != 200 {
+		t.Errorf("expected an UPSERT to cost double the value chars, got %d", chars)
+	}
+}
+
+func TestRoutingPolicyRoundTrip(t *testing.T) {
+	ep := endpoint.NewEndpoint("weighted.example.com", "1.2.3.4", endpoint.RecordTypeA)
+	ep.SetIdentifier = "primary"
+	ep.SetProviderSpecificProperty(providerSpecificWeight, "10")
+	ep.SetProviderSpecificProperty(providerSpecificRegion, "us-east-1")
+	ep.SetProviderSpecificProperty(providerSpecificFailover, "PRIMARY")
+	ep.SetProviderSpecificProperty(providerSpecificGeolocationCountryCode, "US")
+	ep.SetProviderSpecificProperty(providerSpecificHealthCheckID, "hc-1234")
+
+	change := newChange(route53.ChangeActionCreate, ep)
+	rrset := change.ResourceRecordSet
+
+	if got := aws.StringValue(rrset.SetIdentifier); got != ep.SetIdentifier {
+		t.Errorf("expected SetIdentifier %q on the rrset, got %q", ep.SetIdentifier, got)
+	}
+	if rrset.Weight == nil || *rrset.Weight != 10 {
+		t.Errorf("expected Weight 10 on the rrset, got %v", rrset.Weight)
+	}
+	if got := aws.StringValue(rrset.Region); got != "us-east-1" {
+		t.Errorf("expected Region us-east-1 on the rrset, got %q", got)
+	}
+	if got := aws.StringValue(rrset.Failover); got != "PRIMARY" {
+		t.Errorf("expected Failover PRIMARY on the rrset, got %q", got)
+	}
+	if rrset.GeoLocation == nil || aws.StringValue(rrset.GeoLocation.CountryCode) != "US" {
+		t.Errorf("expected GeoLocation.CountryCode US on the rrset, got %v", rrset.GeoLocation)
+	}
+	if got := aws.StringValue(rrset.HealthCheckId); got != "hc-1234" {
+		t.Errorf("expected HealthCheckId hc-1234 on the rrset, got %q", got)
+	}
+
+	roundTripped := endpoint.NewEndpoint(ep.DNSName, ep.Target, ep.RecordType)
+	setEndpointRoutingPolicy(roundTripped, rrset)
+
+	if roundTripped.SetIdentifier != ep.SetIdentifier {
+		t.Errorf("expected SetIdentifier to round-trip as %q, got %q", ep.SetIdentifier, roundTripped.SetIdentifier)
+	}
+	for _, key := range []string{
+		providerSpecificWeight,
+		providerSpecificRegion,
+		providerSpecificFailover,
+		providerSpecificGeolocationCountryCode,
+		providerSpecificHealthCheckID,
+	} {
+		want, _ := ep.GetProviderSpecificProperty(key)
+		got, ok := roundTripped.GetProviderSpecificProperty(key)
+		if !ok || got != want {
+			t.Errorf("expected %s to round-trip as %q, got %q (present=%v)", key, want, got, ok)
+		}
+	}
+}
+
+// route53APIStub is a minimal, in-memory Route53API implementation used to
+// unit test logic that would otherwise require a real AWS account.
+type route53APIStub struct {
+	Route53API
+
+	tagsByZoneID  map[string]map[string]string
+	listTagsCalls []*route53.ListTagsForResourcesInput
+}
+
+func (s *route53APIStub) ListTagsForResources(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error) {
+	s.listTagsCalls = append(s.listTagsCalls, input)
+
+	out := &route53.ListTagsForResourcesOutput{}
+	for _, id := range input.ResourceIds {
+		tags := s.tagsByZoneID[aws.StringValue(id)]
+
+		rts := &route53.ResourceTagSet{
+			ResourceId:   id,
+			ResourceType: input.ResourceType,
+		}
+		for k, v := range tags {
+			rts.Tags = append(rts.Tags, &route53.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		out.ResourceTagSets = append(out.ResourceTagSets, rts)
+	}
+
+	return out, nil
+}
+
+func TestTagFilterZonesBatchingAndPartialMatch(t *testing.T) {
+	const zoneCount = 15
+
+	zones := make(map[string]*route53.HostedZone, zoneCount)
+	tagsByZoneID := make(map[string]map[string]string, zoneCount)
+
+	for i := 0; i < zoneCount; i++ {
+		id := fmt.Sprintf("Z%d", i)
+		prefixedID := "/hostedzone/" + id
+		zones[prefixedID] = &route53.HostedZone{Id: aws.String(prefixedID)}
+
+		env := "dev"
+		if i%2 == 0 {
+			env = "prod"
+		}
+		tagsByZoneID[id] = map[string]string{"env": env}
+	}
+
+	stub := &route53APIStub{tagsByZoneID: tagsByZoneID}
+	p := &AWSProvider{client: stub, zoneTagFilter: NewZoneTagFilter([]string{"env=prod"})}
+
+	if err := p.tagFilterZones(zones); err != nil {
+		t.Fatalf("tagFilterZones returned an error: %v", err)
+	}
+
+	if len(zones) != 8 {
+		t.Errorf("expected 8 zones tagged env=prod to survive, got %d", len(zones))
+	}
+	for prefixedID := range zones {
+		id := cleanZoneID(prefixedID)
+		if tagsByZoneID[id]["env"] != "prod" {
+			t.Errorf("zone %s survived the filter but is not tagged env=prod", prefixedID)
+		}
+	}
+
+	if len(stub.listTagsCalls) != 2 {
+		t.Fatalf("expected zones to be looked up in 2 batches of <= %d, got %d calls", route53TagsForResourcesLimit, len(stub.listTagsCalls))
+	}
+	for _, call := range stub.listTagsCalls {
+		if len(call.ResourceIds) > route53TagsForResourcesLimit {
+			t.Errorf("batch exceeds ListTagsForResources limit: %d > %d", len(call.ResourceIds), route53TagsForResourcesLimit)
+		}
+	}
+}
+
+// changeResourceRecordSetsStub is a Route53API stub whose
+// ChangeResourceRecordSets returns a throttling error a fixed number of
+// times before succeeding, so changeResourceRecordSetsWithRetry's backoff
+// loop can be exercised without a real AWS account.
+type changeResourceRecordSetsStub struct {
+	Route53API
+
+	throttleCount int
+	calls         int
+	err           error
+}
+
+func (s *changeResourceRecordSetsStub) ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	s.calls++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.calls <= s.throttleCount {
+		return nil, awserr.New("ThrottlingException", "rate exceeded", nil)
+	}
+
+	return &route53.ChangeResourceRecordSetsOutput{}, nil
+}
+
+func TestChangeResourceRecordSetsWithRetrySucceedsAfterThrottling(t *testing.T) {
+	stub := &changeResourceRecordSetsStub{throttleCount: 2}
+	p := &AWSProvider{client: stub, batchChangeInterval: time.Millisecond, maxRetries: defaultMaxRetries}
+
+	if _, err := p.changeResourceRecordSetsWithRetry(&route53.ChangeResourceRecordSetsInput{}); err != nil {
+		t.Fatalf("expected the change to eventually succeed, got error: %v", err)
+	}
+
+	if stub.calls != 3 {
+		t.Errorf("expected 2 throttled attempts followed by 1 success (3 calls total), got %d", stub.calls)
+	}
+}
+
+func TestChangeResourceRecordSetsWithRetryGivesUpOnNonRetryableError(t *testing.T) {
+	stub := &changeResourceRecordSetsStub{err: awserr.New("InvalidInput", "bad input", nil)}
+	p := &AWSProvider{client: stub, batchChangeInterval: time.Millisecond, maxRetries: defaultMaxRetries}
+
+	if _, err := p.changeResourceRecordSetsWithRetry(&route53.ChangeResourceRecordSetsInput{}); err == nil {
+		t.Fatal("expected a non-retryable error to be returned")
+	}
+
+	if stub.calls != 1 {
+		t.Errorf("expected a non-retryable error to return immediately without retrying, got %d calls", stub.calls)
+	}
+}
+
+func TestChangeResourceRecordSetsWithRetryExhaustsRetries(t *testing.T) {
+	stub := &changeResourceRecordSetsStub{throttleCount: 1000}
+	p := &AWSProvider{client: stub, batchChangeInterval: time.Millisecond, maxRetries: 2}
+
+	if _, err := p.changeResourceRecordSetsWithRetry(&route53.ChangeResourceRecordSetsInput{}); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+
+	if stub.calls != 3 {
+		t.Errorf("expected maxRetries+1 attempts (3), got %d", stub.calls)
+	}
+}
+
+func TestNewAWSProviderHonorsCustomEndpointAndRegion(t *testing.T) {
+	p, err := NewAWSProvider(AWSConfig{
+		Region:      "us-west-2",
+		APIEndpoint: "http://localhost:4566",
+	})
+	if err != nil {
+		t.Fatalf("NewAWSProvider: %v", err)
+	}
+
+	client, ok := p.client.(*route53.Route53)
+	if !ok {
+		t.Fatalf("expected *route53.Route53 client, got %T", p.client)
+	}
+
+	if got := aws.StringValue(client.Config.Endpoint); got != "http://localhost:4566" {
+		t.Errorf("expected custom endpoint to be honored, got %q", got)
+	}
+	if got := aws.StringValue(client.Config.Region); got != "us-west-2" {
+		t.Errorf("expected region to be honored, got %q", got)
+	}
+}
+
+func TestNewAWSProviderBuildsAssumeRoleCredentials(t *testing.T) {
+	p, err := NewAWSProvider(AWSConfig{
+		Region:        "us-east-1",
+		AssumeRoleARN: "arn:aws:iam::123456789012:role/external-dns",
+	})
+	if err != nil {
+		t.Fatalf("NewAWSProvider: %v", err)
+	}
+
+	client, ok := p.client.(*route53.Route53)
+	if !ok {
+		t.Fatalf("expected *route53.Route53 client, got %T", p.client)
+	}
+
+	if client.Config.Credentials == nil {
+		t.Fatal("expected credentials to be configured for the AssumeRole path")
+	}
+}
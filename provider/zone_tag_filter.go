@@ -0,0 +1,73 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import "strings"
+
+// ZoneTagFilter holds a list of required key=value tag pairs a hosted zone
+// must carry, all of them, in order to be managed.
+type ZoneTagFilter struct {
+	tags map[string]string
+}
+
+// NewZoneTagFilter returns a new ZoneTagFilter given a list of "key=value"
+// filter expressions, as supplied on the command line.
+func NewZoneTagFilter(tagFilters []string) ZoneTagFilter {
+	tags := make(map[string]string)
+
+	for _, tagFilter := range tagFilters {
+		if tagFilter == "" {
+			continue
+		}
+
+		parts := strings.SplitN(tagFilter, "=", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		} else {
+			tags[parts[0]] = ""
+		}
+	}
+
+	return ZoneTagFilter{tags: tags}
+}
+
+// Match checks whether the given zone tags satisfy every key=value pair the
+// filter requires. A filter with an empty value only requires the key to be
+// present, with any value.
+func (f ZoneTagFilter) Match(tags map[string]string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	for k, v := range f.tags {
+		tagValue, ok := tags[k]
+		if !ok {
+			return false
+		}
+		if v != "" && tagValue != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsEmpty returns true if the filter carries no required tags, i.e. it
+// matches every zone.
+func (f ZoneTagFilter) IsEmpty() bool {
+	return len(f.tags) == 0
+}